@@ -0,0 +1,236 @@
+// Package config resolves OctoScreen's configuration from an XDG TOML
+// file, environment variables, and CLI flags, in that order of
+// increasing precedence, and persists edits back to the file.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/BurntSushi/toml"
+	"github.com/shibukawa/configdir"
+)
+
+const (
+	vendorName = "octoscreen"
+	appName    = "octoscreen"
+	fileName   = "config.toml"
+)
+
+// Config holds every setting the Settings panel exposes, plus the ones
+// only reachable today via CLI flags or package vars.
+type Config struct {
+	Endpoint string `toml:"endpoint"`
+	APIKey   string `toml:"api_key"`
+	Lang     string `toml:"lang"`
+	Theme    string `toml:"theme"`
+	Width    int    `toml:"width"`
+	Height   int    `toml:"height"`
+
+	// WatchdogInterval is how often the connection to OctoPrint is
+	// polled, in seconds.
+	WatchdogInterval int `toml:"watchdog_interval"`
+
+	// ErrMercyPeriod is how long a connection error is tolerated,
+	// in seconds, before it's surfaced on the splash screen.
+	ErrMercyPeriod int `toml:"err_mercy_period"`
+
+	MetricsListen string `toml:"metrics_listen"`
+	MetricsPath   string `toml:"metrics_path"`
+
+	// path is where Load found this config, and where Save writes it
+	// back to. It is not itself persisted.
+	path string `toml:"-"`
+}
+
+// Default returns the configuration OctoScreen uses when no file, env
+// var, or flag overrides a given field.
+func Default() *Config {
+	return &Config{
+		Width:            800,
+		Height:           480,
+		Lang:             "en",
+		Theme:            "dark",
+		WatchdogInterval: 5,
+		ErrMercyPeriod:   30,
+		MetricsPath:      "/metrics",
+	}
+}
+
+// configDirs is the XDG search path: $XDG_CONFIG_HOME/octoscreen (or its
+// OS-appropriate fallback) for both reading and writing.
+func configDirs() *configdir.Config {
+	dirs := configdir.New(vendorName, appName)
+	return dirs.QueryFolders(configdir.Global)[0]
+}
+
+// Path returns the config.toml path Load/Save use, creating the
+// directory it lives in if necessary.
+func Path() (string, error) {
+	dir := configDirs()
+	if err := dir.MkdirAll(); err != nil {
+		return "", fmt.Errorf("creating config dir: %s", err)
+	}
+
+	return filepath.Join(dir.Path, fileName), nil
+}
+
+// Dir resolves a path under OctoScreen's XDG config root — the same root
+// config.toml itself lives in — joined with elem. It's the single place
+// $XDG_CONFIG_HOME resolution happens; themes and translations build
+// their own subdirectory from it, and the idle-screen layout its
+// layout.conf path, instead of each re-implementing the XDG fallback.
+func Dir(elem ...string) string {
+	return filepath.Join(append([]string{configDirs().Path}, elem...)...)
+}
+
+// Load resolves the configuration from, in increasing order of
+// precedence: Default(), the TOML file at its XDG path, environment
+// variables (OCTOSCREEN_ENDPOINT, OCTOSCREEN_API_KEY, OCTOSCREEN_LANG,
+// OCTOSCREEN_THEME, OCTOSCREEN_METRICS_LISTEN, OCTOSCREEN_METRICS_PATH,
+// OCTOSCREEN_WATCHDOG_INTERVAL), and finally the flag overrides passed in
+// via overlay (nil is fine — pass a Config built from CLI flags, leaving
+// fields at their zero value when the flag wasn't set).
+func Load(overlay *Config) (*Config, error) {
+	cfg := Default()
+
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.path = path
+
+	if data, err := os.ReadFile(path); err == nil {
+		if err := toml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parsing %s: %s", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading %s: %s", path, err)
+	}
+
+	cfg.path = path
+	applyEnv(cfg)
+
+	if overlay != nil {
+		applyOverlay(cfg, overlay)
+	}
+
+	return cfg, nil
+}
+
+func applyEnv(cfg *Config) {
+	if v := os.Getenv("OCTOSCREEN_ENDPOINT"); v != "" {
+		cfg.Endpoint = v
+	}
+
+	if v := os.Getenv("OCTOSCREEN_API_KEY"); v != "" {
+		cfg.APIKey = v
+	}
+
+	if v := os.Getenv("OCTOSCREEN_LANG"); v != "" {
+		cfg.Lang = v
+	}
+
+	if v := os.Getenv("OCTOSCREEN_THEME"); v != "" {
+		cfg.Theme = v
+	}
+
+	if v := os.Getenv("OCTOSCREEN_METRICS_LISTEN"); v != "" {
+		cfg.MetricsListen = v
+	}
+
+	if v := os.Getenv("OCTOSCREEN_METRICS_PATH"); v != "" {
+		cfg.MetricsPath = v
+	}
+
+	if v := os.Getenv("OCTOSCREEN_WATCHDOG_INTERVAL"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.WatchdogInterval = n
+		}
+	}
+}
+
+// applyOverlay copies every non-zero field of overlay onto cfg, letting
+// CLI flags win over file and env values without needing to know which
+// flags were explicitly set.
+func applyOverlay(cfg, overlay *Config) {
+	if overlay.Endpoint != "" {
+		cfg.Endpoint = overlay.Endpoint
+	}
+
+	if overlay.APIKey != "" {
+		cfg.APIKey = overlay.APIKey
+	}
+
+	if overlay.Lang != "" {
+		cfg.Lang = overlay.Lang
+	}
+
+	if overlay.Theme != "" {
+		cfg.Theme = overlay.Theme
+	}
+
+	if overlay.Width != 0 {
+		cfg.Width = overlay.Width
+	}
+
+	if overlay.Height != 0 {
+		cfg.Height = overlay.Height
+	}
+
+	if overlay.WatchdogInterval != 0 {
+		cfg.WatchdogInterval = overlay.WatchdogInterval
+	}
+
+	if overlay.ErrMercyPeriod != 0 {
+		cfg.ErrMercyPeriod = overlay.ErrMercyPeriod
+	}
+
+	if overlay.MetricsListen != "" {
+		cfg.MetricsListen = overlay.MetricsListen
+	}
+
+	if overlay.MetricsPath != "" {
+		cfg.MetricsPath = overlay.MetricsPath
+	}
+}
+
+// Save atomically rewrites the config file: it encodes to a temp file in
+// the same directory, then renames over the target, so a reader never
+// observes a partially-written file.
+func (cfg *Config) Save() error {
+	path := cfg.path
+	if path == "" {
+		p, err := Path()
+		if err != nil {
+			return err
+		}
+
+		path = p
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), fileName+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temp config file: %s", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := toml.NewEncoder(tmp).Encode(cfg); err != nil {
+		tmp.Close()
+		return fmt.Errorf("encoding config: %s", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing temp config file: %s", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replacing %s: %s", path, err)
+	}
+
+	cfg.path = path
+	return nil
+}
@@ -0,0 +1,105 @@
+package config
+
+import "testing"
+
+func TestApplyEnv(t *testing.T) {
+	cfg := Default()
+
+	t.Setenv("OCTOSCREEN_ENDPOINT", "http://printer.local")
+	t.Setenv("OCTOSCREEN_API_KEY", "secret")
+	t.Setenv("OCTOSCREEN_LANG", "de")
+	t.Setenv("OCTOSCREEN_THEME", "light")
+	t.Setenv("OCTOSCREEN_METRICS_LISTEN", ":9100")
+	t.Setenv("OCTOSCREEN_METRICS_PATH", "/custom-metrics")
+	t.Setenv("OCTOSCREEN_WATCHDOG_INTERVAL", "10")
+
+	applyEnv(cfg)
+
+	if cfg.Endpoint != "http://printer.local" {
+		t.Errorf("Endpoint = %q, want %q", cfg.Endpoint, "http://printer.local")
+	}
+
+	if cfg.APIKey != "secret" {
+		t.Errorf("APIKey = %q, want %q", cfg.APIKey, "secret")
+	}
+
+	if cfg.Lang != "de" {
+		t.Errorf("Lang = %q, want %q", cfg.Lang, "de")
+	}
+
+	if cfg.Theme != "light" {
+		t.Errorf("Theme = %q, want %q", cfg.Theme, "light")
+	}
+
+	if cfg.MetricsListen != ":9100" {
+		t.Errorf("MetricsListen = %q, want %q", cfg.MetricsListen, ":9100")
+	}
+
+	if cfg.MetricsPath != "/custom-metrics" {
+		t.Errorf("MetricsPath = %q, want %q", cfg.MetricsPath, "/custom-metrics")
+	}
+
+	if cfg.WatchdogInterval != 10 {
+		t.Errorf("WatchdogInterval = %d, want 10", cfg.WatchdogInterval)
+	}
+}
+
+func TestApplyEnvLeavesUnsetFieldsAlone(t *testing.T) {
+	cfg := Default()
+	want := *cfg
+
+	applyEnv(cfg)
+
+	if *cfg != want {
+		t.Errorf("applyEnv with no env vars set = %+v, want unchanged %+v", *cfg, want)
+	}
+}
+
+func TestApplyEnvInvalidWatchdogIntervalIsIgnored(t *testing.T) {
+	cfg := Default()
+	want := cfg.WatchdogInterval
+
+	t.Setenv("OCTOSCREEN_WATCHDOG_INTERVAL", "not-a-number")
+	applyEnv(cfg)
+
+	if cfg.WatchdogInterval != want {
+		t.Errorf("WatchdogInterval = %d, want unchanged %d", cfg.WatchdogInterval, want)
+	}
+}
+
+func TestApplyOverlay(t *testing.T) {
+	cfg := Default()
+	cfg.Endpoint = "http://file.local"
+	cfg.Theme = "dark"
+
+	overlay := &Config{
+		Endpoint: "http://flag.local",
+		Width:    1024,
+	}
+
+	applyOverlay(cfg, overlay)
+
+	if cfg.Endpoint != "http://flag.local" {
+		t.Errorf("Endpoint = %q, want overlay value %q", cfg.Endpoint, "http://flag.local")
+	}
+
+	if cfg.Width != 1024 {
+		t.Errorf("Width = %d, want overlay value 1024", cfg.Width)
+	}
+
+	// Theme wasn't set on the overlay, so the file's value should survive.
+	if cfg.Theme != "dark" {
+		t.Errorf("Theme = %q, want untouched %q", cfg.Theme, "dark")
+	}
+}
+
+func TestApplyOverlayZeroValuesDontOverwrite(t *testing.T) {
+	cfg := Default()
+	want := *cfg
+
+	applyOverlay(cfg, &Config{})
+
+	if *cfg != want {
+		t.Errorf("applyOverlay with an empty overlay = %+v, want unchanged %+v", *cfg, want)
+	}
+}
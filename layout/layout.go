@@ -0,0 +1,145 @@
+// Package layout parses the tiny DSL used to describe the idle screen's
+// grid of action tiles, e.g.:
+//
+//	2:move home
+//	extrude/2 temperature files
+//	control network system
+//
+// Each line is a grid row; tokens within a line, separated by spaces, are
+// columns. A "N:" prefix on a token makes it span N columns; a "/W"
+// suffix records weight W on the tile (Tile.Weight).
+// Tokens name a registered tile factory (see Validate).
+package layout
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Tile is one token of a parsed layout: a named tile occupying ColSpan
+// columns with the given Weight, at Line/Col for error reporting.
+type Tile struct {
+	Name    string
+	ColSpan int
+	Weight  int
+
+	Line, Col int
+}
+
+// Layout is a parsed grid: one []Tile per row.
+type Layout struct {
+	Rows [][]Tile
+}
+
+// Default is the layout OctoScreen uses when no user config is present,
+// matching the fixed tile arrangement the idle screen shipped with
+// before the layout DSL existed, plus the Settings entry point it grew
+// once editing these tiles became possible.
+const Default = "move home extrude temperature\nfiles control network system\nsettings"
+
+// Error is returned by Parse/Validate and points at the offending line
+// and column so users can find the mistake in their own file.
+type Error struct {
+	Line, Col int
+	Msg       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("layout:%d:%d: %s", e.Line, e.Col, e.Msg)
+}
+
+// ParseFile reads and parses the layout DSL from path.
+func ParseFile(path string) (*Layout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(string(data))
+}
+
+// Parse parses the layout DSL from a string, as described in the package
+// doc comment.
+func Parse(data string) (*Layout, error) {
+	l := &Layout{}
+
+	for lineNo, line := range strings.Split(data, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		var row []Tile
+		pos := 0
+		for _, field := range strings.Fields(line) {
+			offset := strings.Index(line[pos:], field)
+			pos += offset
+
+			tile, err := parseToken(field, lineNo+1, pos+1)
+			if err != nil {
+				return nil, err
+			}
+
+			row = append(row, tile)
+			pos += len(field)
+		}
+
+		l.Rows = append(l.Rows, row)
+	}
+
+	if len(l.Rows) == 0 {
+		return nil, &Error{1, 1, "layout must declare at least one row"}
+	}
+
+	return l, nil
+}
+
+// parseToken parses a single "[N:]name[/W]" token.
+func parseToken(token string, line, col int) (Tile, error) {
+	t := Tile{ColSpan: 1, Weight: 1, Line: line, Col: col}
+
+	name := token
+	if i := strings.Index(name, ":"); i != -1 {
+		span, err := strconv.Atoi(name[:i])
+		if err != nil || span < 1 {
+			return Tile{}, &Error{line, col, fmt.Sprintf("invalid column span %q", name[:i])}
+		}
+
+		t.ColSpan = span
+		name = name[i+1:]
+	}
+
+	if i := strings.Index(name, "/"); i != -1 {
+		weight, err := strconv.Atoi(name[i+1:])
+		if err != nil || weight < 1 {
+			return Tile{}, &Error{line, col, fmt.Sprintf("invalid weight %q", name[i+1:])}
+		}
+
+		t.Weight = weight
+		name = name[:i]
+	}
+
+	if name == "" {
+		return Tile{}, &Error{line, col, "empty tile name"}
+	}
+
+	t.Name = name
+	return t, nil
+}
+
+// Validate checks that every tile name in the layout is present in
+// known (typically the set of registered tile factories), returning an
+// Error pointing at the first unrecognized token.
+func (l *Layout) Validate(known map[string]bool) error {
+	for _, row := range l.Rows {
+		for _, tile := range row {
+			if !known[tile.Name] {
+				return &Error{tile.Line, tile.Col, fmt.Sprintf("unknown tile %q", tile.Name)}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	l, err := Parse("2:move home\nextrude/2 temperature files")
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+
+	if len(l.Rows) != 2 {
+		t.Fatalf("len(Rows) = %d, want 2", len(l.Rows))
+	}
+
+	row0 := l.Rows[0]
+	if len(row0) != 2 || row0[0].Name != "move" || row0[0].ColSpan != 2 || row0[1].Name != "home" {
+		t.Fatalf("row 0 = %#v, want move (span 2) then home", row0)
+	}
+
+	row1 := l.Rows[1]
+	if len(row1) != 3 || row1[0].Name != "extrude" || row1[0].Weight != 2 {
+		t.Fatalf("row 1 = %#v, want extrude with weight 2 first", row1)
+	}
+}
+
+func TestParseSkipsBlankLinesAndComments(t *testing.T) {
+	l, err := Parse("move\n\n# a comment\nhome\n")
+	if err != nil {
+		t.Fatalf("Parse: %s", err)
+	}
+
+	if len(l.Rows) != 2 {
+		t.Fatalf("len(Rows) = %d, want 2 (blank line and comment should be skipped)", len(l.Rows))
+	}
+}
+
+func TestParseEmptyIsAnError(t *testing.T) {
+	if _, err := Parse("\n\n"); err == nil {
+		t.Fatal("expected an error for a layout with no rows")
+	}
+}
+
+func TestParseToken(t *testing.T) {
+	tests := []struct {
+		token         string
+		wantName      string
+		wantSpan      int
+		wantWeight    int
+		wantErrSubstr string
+	}{
+		{token: "move", wantName: "move", wantSpan: 1, wantWeight: 1},
+		{token: "2:move", wantName: "move", wantSpan: 2, wantWeight: 1},
+		{token: "move/3", wantName: "move", wantSpan: 1, wantWeight: 3},
+		{token: "2:move/3", wantName: "move", wantSpan: 2, wantWeight: 3},
+		{token: "0:move", wantErrSubstr: "invalid column span"},
+		{token: "move/0", wantErrSubstr: "invalid weight"},
+		{token: "x:move", wantErrSubstr: "invalid column span"},
+		{token: "2:", wantErrSubstr: "empty tile name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			tile, err := parseToken(tt.token, 1, 1)
+			if tt.wantErrSubstr != "" {
+				if err == nil {
+					t.Fatalf("parseToken(%q) = nil error, want one containing %q", tt.token, tt.wantErrSubstr)
+				}
+
+				if !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("parseToken(%q) error = %q, want one containing %q", tt.token, err, tt.wantErrSubstr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseToken(%q): %s", tt.token, err)
+			}
+
+			if tile.Name != tt.wantName || tile.ColSpan != tt.wantSpan || tile.Weight != tt.wantWeight {
+				t.Errorf("parseToken(%q) = %+v, want name=%s span=%d weight=%d",
+					tt.token, tile, tt.wantName, tt.wantSpan, tt.wantWeight)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	l, err := Parse(Default)
+	if err != nil {
+		t.Fatalf("Parse(Default): %s", err)
+	}
+
+	known := map[string]bool{
+		"move": true, "home": true, "extrude": true, "temperature": true,
+		"files": true, "control": true, "network": true, "system": true,
+		"settings": true,
+	}
+
+	if err := l.Validate(known); err != nil {
+		t.Errorf("Validate(known) = %s, want nil", err)
+	}
+
+	delete(known, "settings")
+	if err := l.Validate(known); err == nil {
+		t.Error("Validate should reject a layout referencing an unregistered tile")
+	}
+}
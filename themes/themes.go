@@ -0,0 +1,183 @@
+// Package themes catalogs OctoScreen's built-in CSS themes and any custom
+// ones a user drops into their XDG config directory.
+package themes
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/thingspi/OctoScreen/config"
+)
+
+//go:embed *.css *.toml
+var builtin embed.FS
+
+// Default is the theme OctoScreen selects when none is configured.
+const Default = "dark"
+
+// Manifest describes a theme: which CSS to load and how it should be
+// presented in a theme picker.
+type Manifest struct {
+	Name         string `toml:"name"`
+	Author       string `toml:"author"`
+	ScaleFactors []int  `toml:"scaleFactors"`
+
+	// id is the lookup key (the file's base name, e.g. "dark"), set by
+	// the loader rather than the manifest file itself.
+	id   string
+	path string
+	css  []byte
+}
+
+// ID is the lookup key used with Load, e.g. "dark" or "my-theme".
+func (m *Manifest) ID() string { return m.id }
+
+// CSS returns the theme's stylesheet contents.
+func (m *Manifest) CSS() []byte { return m.css }
+
+// SupportsScale reports whether the theme declares support for the given
+// scale factor. A manifest with no ScaleFactors is assumed to support all
+// of them (this is the case for custom user themes with no manifest).
+func (m *Manifest) SupportsScale(factor int) bool {
+	if len(m.ScaleFactors) == 0 {
+		return true
+	}
+
+	for _, f := range m.ScaleFactors {
+		if f == factor {
+			return true
+		}
+	}
+
+	return false
+}
+
+// List returns every built-in theme plus any custom *.css file found in
+// dir (the XDG themes directory), sorted by ID with built-ins first.
+func List(dir string) ([]*Manifest, error) {
+	entries, err := builtin.ReadDir(".")
+	if err != nil {
+		return nil, fmt.Errorf("reading built-in themes: %s", err)
+	}
+
+	var manifests []*Manifest
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".css") {
+			continue
+		}
+
+		id := strings.TrimSuffix(entry.Name(), ".css")
+		m, err := loadBuiltin(id)
+		if err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, m)
+	}
+
+	if dir != "" {
+		custom, err := listCustom(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, custom...)
+	}
+
+	return manifests, nil
+}
+
+// Load resolves id to a theme, checking dir (the XDG themes directory)
+// before falling back to the built-in catalog.
+func Load(id, dir string) (*Manifest, error) {
+	if dir != "" {
+		path := filepath.Join(dir, id+".css")
+		if css, err := os.ReadFile(path); err == nil {
+			m := loadManifestFile(filepath.Join(dir, id+".toml"))
+			m.id = id
+			m.path = path
+			m.css = css
+			if m.Name == "" {
+				m.Name = id
+			}
+
+			return m, nil
+		}
+	}
+
+	return loadBuiltin(id)
+}
+
+func loadBuiltin(id string) (*Manifest, error) {
+	css, err := builtin.ReadFile(id + ".css")
+	if err != nil {
+		return nil, fmt.Errorf("unknown theme %q: %s", id, err)
+	}
+
+	m := &Manifest{id: id, css: css}
+	if data, err := builtin.ReadFile(id + ".toml"); err == nil {
+		if err := toml.Unmarshal(data, m); err != nil {
+			return nil, fmt.Errorf("parsing manifest for theme %q: %s", id, err)
+		}
+	}
+
+	if m.Name == "" {
+		m.Name = id
+	}
+
+	return m, nil
+}
+
+func listCustom(dir string) ([]*Manifest, error) {
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading themes dir %q: %s", dir, err)
+	}
+
+	var manifests []*Manifest
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".css") {
+			continue
+		}
+
+		id := strings.TrimSuffix(f.Name(), ".css")
+		m, err := Load(id, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		manifests = append(manifests, m)
+	}
+
+	return manifests, nil
+}
+
+// loadManifestFile best-effort loads a manifest; a missing or malformed
+// file yields a zero-value Manifest rather than an error, since custom
+// themes aren't required to ship one.
+func loadManifestFile(path string) *Manifest {
+	m := &Manifest{}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m
+	}
+
+	if err := toml.Unmarshal(data, m); err != nil {
+		return &Manifest{}
+	}
+
+	return m
+}
+
+// ConfigDir returns the "themes" subdirectory of OctoScreen's XDG config
+// root (see config.Dir).
+func ConfigDir() string {
+	return config.Dir("themes")
+}
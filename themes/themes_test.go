@@ -0,0 +1,50 @@
+package themes
+
+import "testing"
+
+func TestManifestSupportsScale(t *testing.T) {
+	tests := []struct {
+		name         string
+		scaleFactors []int
+		factor       int
+		want         bool
+	}{
+		{"declared factor matches", []int{1, 2, 3}, 2, true},
+		{"declared factor doesn't match", []int{1, 2}, 3, false},
+		{"no factors declared supports everything", nil, 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manifest{ScaleFactors: tt.scaleFactors}
+			if got := m.SupportsScale(tt.factor); got != tt.want {
+				t.Errorf("SupportsScale(%d) = %v, want %v", tt.factor, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadBuiltin(t *testing.T) {
+	m, err := loadBuiltin(Default)
+	if err != nil {
+		t.Fatalf("loadBuiltin(%q): %s", Default, err)
+	}
+
+	if m.ID() != Default {
+		t.Errorf("ID() = %q, want %q", m.ID(), Default)
+	}
+
+	if len(m.CSS()) == 0 {
+		t.Error("CSS() is empty for the built-in default theme")
+	}
+
+	if m.Name == "" {
+		t.Error("Name is empty for the built-in default theme")
+	}
+}
+
+func TestLoadBuiltinUnknown(t *testing.T) {
+	if _, err := loadBuiltin("does-not-exist"); err == nil {
+		t.Fatal("expected an error for an unknown theme")
+	}
+}
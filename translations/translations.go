@@ -0,0 +1,224 @@
+// Package translations loads OctoScreen's UI string catalogs and resolves
+// a given locale against them, falling back to English for any missing key.
+package translations
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/thingspi/OctoScreen/config"
+)
+
+//go:embed *.toml
+var defaultCatalogs embed.FS
+
+// FallbackLocale is used whenever a key is missing from the requested
+// locale, and as the locale when nothing else can be detected.
+const FallbackLocale = "en"
+
+// catalog is a locale's flat key -> template map, e.g. "connect.refused".
+type catalog map[string]string
+
+// Translations resolves "section.key" identifiers to locale-specific,
+// printf-style format strings.
+type Translations struct {
+	locale   string
+	catalogs map[string]catalog
+	missing  map[string]bool
+}
+
+// Load reads the embedded catalogs plus any user-supplied overrides found
+// in dir (may be empty), and selects locale as the active language. If
+// locale is empty, DetectLocale is used instead.
+func Load(locale, dir string) (*Translations, error) {
+	t := &Translations{
+		catalogs: map[string]catalog{},
+		missing:  map[string]bool{},
+	}
+
+	entries, err := defaultCatalogs.ReadDir(".")
+	if err != nil {
+		return nil, fmt.Errorf("reading embedded translations: %s", err)
+	}
+
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".toml")
+		data, err := defaultCatalogs.ReadFile(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading embedded translation %q: %s", entry.Name(), err)
+		}
+
+		c, err := decodeCatalog(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing embedded translation %q: %s", entry.Name(), err)
+		}
+
+		t.catalogs[name] = c
+	}
+
+	if dir != "" {
+		if err := t.loadUserOverrides(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	if locale == "" {
+		locale = DetectLocale()
+	}
+
+	t.locale = locale
+	return t, nil
+}
+
+// loadUserOverrides merges any *.toml file found in dir on top of the
+// embedded catalog of the same name, letting users extend or correct a
+// shipped translation without a rebuild.
+func (t *Translations) loadUserOverrides(dir string) error {
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("reading translations dir %q: %s", dir, err)
+	}
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".toml") {
+			continue
+		}
+
+		name := strings.TrimSuffix(f.Name(), ".toml")
+		data, err := os.ReadFile(dir + "/" + f.Name())
+		if err != nil {
+			return fmt.Errorf("reading translation override %q: %s", f.Name(), err)
+		}
+
+		c, err := decodeCatalog(data)
+		if err != nil {
+			return fmt.Errorf("parsing translation override %q: %s", f.Name(), err)
+		}
+
+		for k, v := range c {
+			if t.catalogs[name] == nil {
+				t.catalogs[name] = catalog{}
+			}
+			t.catalogs[name][k] = v
+		}
+	}
+
+	return nil
+}
+
+// T resolves key (e.g. "connect.refused") against the active locale,
+// falling back to FallbackLocale, and formats the result with args the
+// same way fmt.Sprintf would. An unresolved key is returned verbatim,
+// wrapped in "???", and recorded so DumpMissing can report it.
+func (t *Translations) T(key string, args ...interface{}) string {
+	tmpl, ok := t.lookup(t.locale, key)
+	if !ok {
+		tmpl, ok = t.lookup(FallbackLocale, key)
+	}
+
+	if !ok {
+		t.missing[key] = true
+		return fmt.Sprintf("???%s???", key)
+	}
+
+	if len(args) == 0 {
+		return tmpl
+	}
+
+	return fmt.Sprintf(tmpl, args...)
+}
+
+func (t *Translations) lookup(locale, key string) (string, bool) {
+	c, ok := t.catalogs[locale]
+	if !ok {
+		return "", false
+	}
+
+	tmpl, ok := c[key]
+	return tmpl, ok
+}
+
+// SetLocale switches the active locale at runtime; subsequent T calls use
+// the new locale's catalog (falling back to English as usual).
+func (t *Translations) SetLocale(locale string) {
+	t.locale = locale
+}
+
+// Locale returns the currently active locale.
+func (t *Translations) Locale() string {
+	return t.locale
+}
+
+// DumpMissing returns every key that has been requested but could not be
+// resolved in either the active locale or FallbackLocale, sorted for
+// stable output. Intended for contributors filling out a new catalog.
+func (t *Translations) DumpMissing() []string {
+	keys := make([]string, 0, len(t.missing))
+	for k := range t.missing {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+// ConfigDir returns the "translations" subdirectory of OctoScreen's XDG
+// config root (see config.Dir). Any *.toml file found here
+// overrides/extends the matching built-in catalog (see Load).
+func ConfigDir() string {
+	return config.Dir("translations")
+}
+
+// DetectLocale inspects LC_MESSAGES and LANG (in that order) for a locale
+// identifier such as "de_DE.UTF-8", returning its language component
+// ("de"), or FallbackLocale if neither variable is set or parseable.
+func DetectLocale() string {
+	for _, env := range []string{"LC_MESSAGES", "LANG"} {
+		v := os.Getenv(env)
+		if v == "" || v == "C" || v == "POSIX" {
+			continue
+		}
+
+		if i := strings.IndexAny(v, ".@"); i != -1 {
+			v = v[:i]
+		}
+
+		if i := strings.Index(v, "_"); i != -1 {
+			v = v[:i]
+		}
+
+		if v != "" {
+			return v
+		}
+	}
+
+	return FallbackLocale
+}
+
+// decodeCatalog parses a TOML document of the form
+//
+//	[section]
+//	key = "value"
+//
+// into a flat "section.key" -> value catalog.
+func decodeCatalog(data []byte) (catalog, error) {
+	var doc map[string]map[string]string
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	c := catalog{}
+	for section, fields := range doc {
+		for key, value := range fields {
+			c[section+"."+key] = value
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,103 @@
+package translations
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeCatalog(t *testing.T) {
+	data := []byte(`
+[connect]
+refused = "nope"
+
+[state]
+ready = "ready"
+`)
+
+	c, err := decodeCatalog(data)
+	if err != nil {
+		t.Fatalf("decodeCatalog: %s", err)
+	}
+
+	want := catalog{
+		"connect.refused": "nope",
+		"state.ready":     "ready",
+	}
+
+	if !reflect.DeepEqual(c, want) {
+		t.Fatalf("decodeCatalog = %#v, want %#v", c, want)
+	}
+}
+
+func TestDecodeCatalogInvalid(t *testing.T) {
+	if _, err := decodeCatalog([]byte("not = [valid")); err == nil {
+		t.Fatal("expected an error for malformed TOML")
+	}
+}
+
+func TestTranslationsFallback(t *testing.T) {
+	tr := &Translations{
+		locale: "de",
+		catalogs: map[string]catalog{
+			"en": {"greeting": "Hello %s"},
+			"de": {"greeting": "Hallo %s"},
+		},
+		missing: map[string]bool{},
+	}
+
+	if got := tr.T("greeting", "World"); got != "Hallo World" {
+		t.Errorf("T(greeting) = %q, want %q", got, "Hallo World")
+	}
+
+	// "farewell" only exists in the English catalog: de should fall
+	// through to it rather than report it missing.
+	tr.catalogs["en"]["farewell"] = "Bye"
+	if got := tr.T("farewell"); got != "Bye" {
+		t.Errorf("T(farewell) = %q, want fallback %q", got, "Bye")
+	}
+
+	if got := tr.T("nope"); got != "???nope???" {
+		t.Errorf("T(nope) = %q, want %q", got, "???nope???")
+	}
+}
+
+func TestDumpMissingIsSorted(t *testing.T) {
+	tr := &Translations{
+		locale:   "en",
+		catalogs: map[string]catalog{"en": {}},
+		missing:  map[string]bool{},
+	}
+
+	tr.T("zebra")
+	tr.T("apple")
+	tr.T("mango")
+
+	got := tr.DumpMissing()
+	want := []string{"apple", "mango", "zebra"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DumpMissing() = %v, want %v", got, want)
+	}
+}
+
+func TestDetectLocale(t *testing.T) {
+	tests := []struct {
+		name, lcMessages, lang, want string
+	}{
+		{"lc_messages wins", "de_DE.UTF-8", "fr_FR.UTF-8", "de"},
+		{"falls back to LANG", "", "fr_FR.UTF-8", "fr"},
+		{"C locale is ignored", "C", "", FallbackLocale},
+		{"nothing set", "", "", FallbackLocale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_MESSAGES", tt.lcMessages)
+			t.Setenv("LANG", tt.lang)
+
+			if got := DetectLocale(); got != tt.want {
+				t.Errorf("DetectLocale() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
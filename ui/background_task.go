@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"sync"
+	"time"
+)
+
+// BackgroundTask runs fn on a repeating interval, starting only once
+// Start is called (bound to the window's "show" signal so it never fires
+// before the UI is ready to react to it).
+type BackgroundTask struct {
+	fn func()
+
+	mu       sync.Mutex
+	interval time.Duration
+	ticker   *time.Ticker
+	done     chan struct{}
+	trigger  chan struct{}
+	reconfig chan func()
+}
+
+// NewBackgroundTask builds a BackgroundTask that calls fn every interval
+// once started.
+func NewBackgroundTask(interval time.Duration, fn func()) *BackgroundTask {
+	return &BackgroundTask{
+		fn:       fn,
+		interval: interval,
+		trigger:  make(chan struct{}, 1),
+		reconfig: make(chan func()),
+	}
+}
+
+// Start begins running fn on the configured interval. Matches the
+// gtk signal handler signature (no args, no return) so it can be passed
+// directly to Window.Connect.
+func (b *BackgroundTask) Start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ticker != nil {
+		return
+	}
+
+	b.ticker = time.NewTicker(b.interval)
+	b.done = make(chan struct{})
+	go b.run(b.ticker, b.done)
+}
+
+// run calls fn on every tick, as soon as TriggerNow is called, or runs a
+// Reconfigure callback, until done is closed. ticker/done are passed in
+// rather than read from b so a restart from SetInterval can't race the
+// previous goroutine tearing down. Routing all three through this one
+// goroutine guarantees fn is never called concurrently with itself, or
+// with anything a Reconfigure callback touches.
+func (b *BackgroundTask) run(ticker *time.Ticker, done chan struct{}) {
+	for {
+		select {
+		case <-ticker.C:
+			b.fn()
+		case <-b.trigger:
+			b.fn()
+		case f := <-b.reconfig:
+			f()
+		case <-done:
+			return
+		}
+	}
+}
+
+// TriggerNow runs fn as soon as possible on the task's own goroutine,
+// without waiting for the next tick, and without risking a concurrent
+// call to fn from the ticker. A pending trigger is not queued twice.
+func (b *BackgroundTask) TriggerNow() {
+	select {
+	case b.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Reconfigure runs fn on the task's own goroutine and blocks until it
+// finishes, so fn can safely touch whatever state the periodic fn (here,
+// verifyConnection) also touches without racing it. If Start hasn't run
+// yet there's no ticker goroutine to race with, so fn runs synchronously
+// on the caller instead of blocking forever waiting for one.
+func (b *BackgroundTask) Reconfigure(fn func()) {
+	b.mu.Lock()
+	running := b.ticker != nil
+	b.mu.Unlock()
+
+	if !running {
+		fn()
+		return
+	}
+
+	done := make(chan struct{})
+	b.reconfig <- func() {
+		fn()
+		close(done)
+	}
+	<-done
+}
+
+// SetInterval changes how often fn runs, restarting the ticker if the
+// task is already running.
+func (b *BackgroundTask) SetInterval(interval time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.interval = interval
+	if b.ticker == nil {
+		return
+	}
+
+	b.ticker.Stop()
+	close(b.done)
+
+	b.ticker = time.NewTicker(b.interval)
+	b.done = make(chan struct{})
+	go b.run(b.ticker, b.done)
+}
+
+// Stop halts the periodic execution of fn.
+func (b *BackgroundTask) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ticker == nil {
+		return
+	}
+
+	b.ticker.Stop()
+	close(b.done)
+	b.ticker = nil
+}
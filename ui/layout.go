@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/gotk3/gotk3/gtk"
+	"github.com/thingspi/OctoScreen/config"
+	"github.com/thingspi/OctoScreen/layout"
+)
+
+// LayoutPath overrides the location of the idle screen's layout config.
+// Empty uses the "layout.conf" file under config.Dir's XDG root.
+var LayoutPath string
+
+// TileFactory builds the widget attached for one idle-screen tile.
+type TileFactory func(ui *UI) gtk.IWidget
+
+// tileFactories is the registry of names the layout DSL may reference.
+// IdleStatusPanel consults it to build each tile's widget; layout.Validate
+// consults its keys to reject unknown tokens. Built-ins are registered
+// from panel_idle.go and panel_settings.go; plugins extend this with
+// RegisterTile.
+var tileFactories = map[string]TileFactory{}
+
+// RegisterTile makes name a valid token in user layout.conf files,
+// building its widget with factory. Plugins call this to add their own
+// idle-screen tiles.
+func RegisterTile(name string, factory TileFactory) {
+	tileFactories[name] = factory
+}
+
+// IdleLayout loads and validates the idle screen's tile layout: the user
+// config at LayoutPath (or its XDG default) if present, otherwise
+// layout.Default. IdleStatusPanel consults this to decide which tiles to
+// attach, and at which grid position, instead of a fixed composition.
+func IdleLayout() (*layout.Layout, error) {
+	path := LayoutPath
+	if path == "" {
+		path = defaultLayoutPath()
+	}
+
+	l, err := layout.ParseFile(path)
+	if os.IsNotExist(err) {
+		l, err = layout.Parse(layout.Default)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(tileFactories))
+	for name := range tileFactories {
+		known[name] = true
+	}
+
+	if err := l.Validate(known); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func defaultLayoutPath() string {
+	return config.Dir("layout.conf")
+}
@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/mcuadros/go-octoprint"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// MetricsListen is the address the Prometheus/OpenMetrics exporter
+	// listens on, e.g. ":9112". Empty (the default) disables it.
+	MetricsListen string
+
+	// MetricsPath is the HTTP path the exporter is served on.
+	MetricsPath = "/metrics"
+)
+
+// metrics holds every gauge/counter the exporter publishes, plus the HTTP
+// server serving them. All fields are safe for concurrent use: the
+// prometheus client library guarantees Set/Inc are goroutine-safe, which
+// is required here since verifyConnection runs on the BackgroundTask
+// goroutine while promhttp serves reads from the net/http goroutine pool.
+type metrics struct {
+	registry *prometheus.Registry
+	server   *http.Server
+
+	uiState          *prometheus.GaugeVec
+	connectionState  *prometheus.GaugeVec
+	hotEndTemp       prometheus.Gauge
+	bedTemp          prometheus.Gauge
+	jobProgress      prometheus.Gauge
+	filamentUsed     prometheus.Gauge
+	printTimeElapsed prometheus.Gauge
+	printTimeLeft    prometheus.Gauge
+	connectionErrors prometheus.Counter
+}
+
+func newMetrics() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+
+		uiState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "octoscreen_ui_state",
+			Help: "Which UI state is currently active (1) or not (0): idle, printing, splash.",
+		}, []string{"state"}),
+
+		connectionState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "octoscreen_printer_connection_state",
+			Help: "Which OctoPrint connection state is currently active; only that state has a series, set to 1.",
+		}, []string{"state"}),
+
+		hotEndTemp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "octoscreen_hotend_temperature_celsius",
+			Help: "Current hot-end temperature, in degrees Celsius.",
+		}),
+
+		bedTemp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "octoscreen_bed_temperature_celsius",
+			Help: "Current bed temperature, in degrees Celsius.",
+		}),
+
+		jobProgress: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "octoscreen_job_progress_percent",
+			Help: "Completion percentage of the current print job.",
+		}),
+
+		filamentUsed: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "octoscreen_job_filament_used_mm",
+			Help: "Filament used by the current print job, in millimeters.",
+		}),
+
+		printTimeElapsed: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "octoscreen_job_print_time_elapsed_seconds",
+			Help: "Elapsed print time of the current print job, in seconds.",
+		}),
+
+		printTimeLeft: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "octoscreen_job_print_time_left_seconds",
+			Help: "Estimated remaining print time of the current print job, in seconds.",
+		}),
+
+		connectionErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "octoscreen_connection_errors_total",
+			Help: "Number of errors encountered while polling the OctoPrint connection state.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		m.uiState,
+		m.connectionState,
+		m.hotEndTemp,
+		m.bedTemp,
+		m.jobProgress,
+		m.filamentUsed,
+		m.printTimeElapsed,
+		m.printTimeLeft,
+		m.connectionErrors,
+	)
+
+	return m
+}
+
+// setUIState records newState as the only active octoscreen_ui_state
+// series, zeroing out the others.
+func (m *metrics) setUIState(newState string) {
+	for _, state := range []string{"idle", "printing", "splash"} {
+		value := 0.0
+		if state == newState {
+			value = 1
+		}
+
+		m.uiState.WithLabelValues(state).Set(value)
+	}
+}
+
+// setConnectionState records state as the only active
+// octoscreen_printer_connection_state series, removing the others.
+func (m *metrics) setConnectionState(state octoprint.ConnectionState) {
+	m.connectionState.Reset()
+	m.connectionState.WithLabelValues(string(state)).Set(1)
+}
+
+// updateJobMetrics pulls temperatures and job progress from OctoPrint and
+// updates the corresponding gauges. Errors are logged, not fatal: a
+// temporarily unreachable printer shouldn't take the exporter down.
+func (m *metrics) updateJobMetrics(client *octoprint.Client) {
+	state, err := (&octoprint.StateRequest{}).Do(client)
+	if err != nil {
+		Logger.Debugf("Error fetching printer state for metrics: %s", err)
+	} else {
+		if tool0, ok := state.Temperature.CurrentTemperatures["tool0"]; ok {
+			m.hotEndTemp.Set(tool0.Actual)
+		}
+
+		if bed, ok := state.Temperature.CurrentTemperatures["bed"]; ok {
+			m.bedTemp.Set(bed.Actual)
+		}
+	}
+
+	job, err := (&octoprint.JobRequest{}).Do(client)
+	if err != nil {
+		Logger.Debugf("Error fetching job state for metrics: %s", err)
+		return
+	}
+
+	m.jobProgress.Set(job.Progress.Completion)
+	m.filamentUsed.Set(job.Job.Filament.Tool0.Length)
+	m.printTimeElapsed.Set(job.Progress.PrintTime)
+	m.printTimeLeft.Set(job.Progress.PrintTimeLeft)
+}
+
+// start begins serving the exporter on MetricsListen if one was
+// configured; otherwise it's a no-op.
+func (m *metrics) start() {
+	if MetricsListen == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+
+	m.server = &http.Server{
+		Addr:    MetricsListen,
+		Handler: mux,
+	}
+
+	go func() {
+		if err := m.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logrus.Errorf("Error serving metrics: %s", err)
+		}
+	}()
+}
+
+// stop gracefully shuts the exporter's HTTP server down, if it was
+// started.
+func (m *metrics) stop() {
+	if m.server == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := m.server.Shutdown(ctx); err != nil {
+		logrus.Errorf("Error shutting down metrics server: %s", err)
+	}
+}
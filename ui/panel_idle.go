@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"github.com/gotk3/gotk3/gtk"
+	"github.com/sirupsen/logrus"
+	"github.com/thingspi/OctoScreen/layout"
+)
+
+func init() {
+	for _, name := range []string{"move", "home", "extrude", "temperature", "files", "control", "network", "system"} {
+		RegisterTile(name, stubTileFactory(name))
+	}
+}
+
+// stubTileFactory builds a labeled tile button for one of OctoScreen's
+// built-in actions. The dedicated per-action panels (move, home,
+// extrude, ...) aren't part of this change; clicking the tile just logs
+// until those panels are wired up the same way SettingsPanel was.
+func stubTileFactory(name string) TileFactory {
+	return func(ui *UI) gtk.IWidget {
+		return MustButtonImage(ui.T("tile."+name), name+".svg", func() {
+			Logger.Debugf("tile %q clicked (no dedicated panel wired up yet)", name)
+		})
+	}
+}
+
+// idlePanel is the idle screen's Panel: a grid of action tiles built
+// from IdleLayout() (the user's layout.conf, or layout.Default when none
+// is present) rather than a fixed composition.
+type idlePanel struct {
+	ui   *UI
+	grid *gtk.Grid
+}
+
+// IdleStatusPanel builds the idle screen, attaching each tile IdleLayout
+// resolves at its declared row, column span and position. Tile.Weight is
+// not applied when attaching tiles.
+func IdleStatusPanel(ui *UI) Panel {
+	p := &idlePanel{ui: ui, grid: MustGrid()}
+
+	l, err := IdleLayout()
+	if err != nil {
+		logrus.Errorf("Error loading idle layout, falling back to default: %s", err)
+		l, _ = layout.Parse(layout.Default)
+	}
+
+	for row, tiles := range l.Rows {
+		col := 0
+		for _, tile := range tiles {
+			factory := tileFactories[tile.Name]
+			if factory != nil {
+				p.grid.Attach(factory(ui), col, row, tile.ColSpan, 1)
+			}
+
+			col += tile.ColSpan
+		}
+	}
+
+	return p
+}
+
+// Refresh rebuilds the idle screen so its tile labels pick up the
+// current locale, and re-attaches it in place of the stale one.
+func (p *idlePanel) Refresh() {
+	p.ui.Add(IdleStatusPanel(p.ui))
+}
+
+func (p *idlePanel) Grid() *gtk.Grid { return p.grid }
+func (p *idlePanel) Show()           { p.grid.Show() }
+func (p *idlePanel) Hide()           { p.grid.Hide() }
+func (p *idlePanel) Parent() Panel   { return nil }
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"strconv"
+
+	"github.com/gotk3/gotk3/gtk"
+	"github.com/thingspi/OctoScreen/config"
+	"github.com/thingspi/OctoScreen/themes"
+)
+
+// SettingsPanel lets the user edit the connection, language, theme,
+// resolution and watchdog settings that used to require a restart (or a
+// CLI flag) to change, and persists them via the config package.
+type SettingsPanel struct {
+	ui     *UI
+	parent Panel
+	grid   *gtk.Grid
+
+	endpoint  *gtk.Entry
+	apiKey    *gtk.Entry
+	lang      *gtk.Entry
+	theme     *gtk.ComboBoxText
+	width     *gtk.Entry
+	height    *gtk.Entry
+	watchdog  *gtk.Entry
+	mercy     *gtk.Entry
+	statusMsg *gtk.Label
+}
+
+// SettingsPanelFactory is the idle-screen tile name this panel is
+// reachable from (see layout.go's tileFactories).
+const SettingsPanelFactory = "settings"
+
+func init() {
+	RegisterTile(SettingsPanelFactory, func(ui *UI) gtk.IWidget {
+		return MustButtonImage(ui.T("tile.settings"), "settings.svg", func() {
+			ui.Add(NewSettingsPanel(ui, ui.Current))
+		})
+	})
+}
+
+// NewSettingsPanel builds the settings panel, pre-filled from ui's
+// current configuration.
+func NewSettingsPanel(ui *UI, parent Panel) *SettingsPanel {
+	p := &SettingsPanel{
+		ui:     ui,
+		parent: parent,
+		grid:   MustGrid(),
+	}
+
+	p.endpoint = MustEntry()
+	p.apiKey = MustEntry()
+	p.apiKey.SetVisibility(false)
+	p.lang = MustEntry()
+	p.theme = MustComboBoxText()
+	p.width = MustEntry()
+	p.height = MustEntry()
+	p.watchdog = MustEntry()
+	p.mercy = MustEntry()
+	p.statusMsg = MustLabel("")
+
+	if cfg := ui.cfg; cfg != nil {
+		p.endpoint.SetText(cfg.Endpoint)
+		p.apiKey.SetText(cfg.APIKey)
+		p.lang.SetText(cfg.Lang)
+		p.width.SetText(strconv.Itoa(cfg.Width))
+		p.height.SetText(strconv.Itoa(cfg.Height))
+		p.watchdog.SetText(strconv.Itoa(cfg.WatchdogInterval))
+		p.mercy.SetText(strconv.Itoa(cfg.ErrMercyPeriod))
+	}
+
+	if available, err := themes.List(themes.ConfigDir()); err == nil {
+		for _, m := range available {
+			p.theme.Append(m.ID(), m.Name)
+		}
+	}
+
+	if ui.cfg != nil {
+		p.theme.SetActiveID(ui.cfg.Theme)
+	}
+
+	save := MustButtonImage(ui.T("settings.save"), "save.svg", p.onSave)
+
+	p.grid.Attach(MustLabel(ui.T("settings.endpoint")), 0, 0, 1, 1)
+	p.grid.Attach(p.endpoint, 1, 0, 1, 1)
+	p.grid.Attach(MustLabel(ui.T("settings.api_key")), 0, 1, 1, 1)
+	p.grid.Attach(p.apiKey, 1, 1, 1, 1)
+	p.grid.Attach(MustLabel(ui.T("settings.language")), 0, 2, 1, 1)
+	p.grid.Attach(p.lang, 1, 2, 1, 1)
+	p.grid.Attach(MustLabel(ui.T("settings.theme")), 0, 3, 1, 1)
+	p.grid.Attach(p.theme, 1, 3, 1, 1)
+	p.grid.Attach(MustLabel(ui.T("settings.width")), 0, 4, 1, 1)
+	p.grid.Attach(p.width, 1, 4, 1, 1)
+	p.grid.Attach(MustLabel(ui.T("settings.height")), 0, 5, 1, 1)
+	p.grid.Attach(p.height, 1, 5, 1, 1)
+	p.grid.Attach(MustLabel(ui.T("settings.watchdog_interval")), 0, 6, 1, 1)
+	p.grid.Attach(p.watchdog, 1, 6, 1, 1)
+	p.grid.Attach(MustLabel(ui.T("settings.err_mercy_period")), 0, 7, 1, 1)
+	p.grid.Attach(p.mercy, 1, 7, 1, 1)
+	p.grid.Attach(save, 0, 8, 2, 1)
+	p.grid.Attach(p.statusMsg, 0, 9, 2, 1)
+
+	return p
+}
+
+// onSave reads the form, saves it to disk, and hot-reloads the running
+// UI without requiring a restart.
+func (p *SettingsPanel) onSave() {
+	cfg, err := config.Load(nil)
+	if err != nil {
+		p.statusMsg.SetText(p.ui.T("connect.unexpected", err))
+		return
+	}
+
+	cfg.Endpoint, _ = p.endpoint.GetText()
+	cfg.APIKey, _ = p.apiKey.GetText()
+	cfg.Lang, _ = p.lang.GetText()
+	cfg.Theme = p.theme.GetActiveID()
+
+	width, _ := p.width.GetText()
+	cfg.Width = atoiOr(width, cfg.Width)
+
+	height, _ := p.height.GetText()
+	cfg.Height = atoiOr(height, cfg.Height)
+
+	watchdog, _ := p.watchdog.GetText()
+	cfg.WatchdogInterval = atoiOr(watchdog, cfg.WatchdogInterval)
+
+	mercy, _ := p.mercy.GetText()
+	cfg.ErrMercyPeriod = atoiOr(mercy, cfg.ErrMercyPeriod)
+
+	if err := cfg.Save(); err != nil {
+		p.statusMsg.SetText(p.ui.T("connect.unexpected", err))
+		return
+	}
+
+	if err := p.ui.ReloadConfig(cfg); err != nil {
+		p.statusMsg.SetText(p.ui.T("connect.unexpected", err))
+		return
+	}
+
+	p.statusMsg.SetText(p.ui.T("settings.saved"))
+}
+
+func atoiOr(s string, fallback int) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fallback
+	}
+
+	return n
+}
+
+// Refresh rebuilds the settings panel so its labels pick up the current
+// locale. The form is refilled from ui's current config, so any unsaved
+// edits in the form fields are discarded.
+func (p *SettingsPanel) Refresh() {
+	p.ui.Add(NewSettingsPanel(p.ui, p.parent))
+}
+
+func (p *SettingsPanel) Grid() *gtk.Grid { return p.grid }
+func (p *SettingsPanel) Show()           { p.grid.Show() }
+func (p *SettingsPanel) Hide()           { p.grid.Hide() }
+func (p *SettingsPanel) Parent() Panel   { return p.parent }
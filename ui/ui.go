@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -11,6 +12,9 @@ import (
 	"github.com/gotk3/gotk3/gtk"
 	"github.com/mcuadros/go-octoprint"
 	"github.com/sirupsen/logrus"
+	"github.com/thingspi/OctoScreen/config"
+	"github.com/thingspi/OctoScreen/themes"
+	"github.com/thingspi/OctoScreen/translations"
 )
 
 var (
@@ -18,6 +22,14 @@ var (
 	WindowName   = "OctoScreen"
 	WindowHeight = 480
 	WindowWidth  = 800
+
+	// Lang overrides the auto-detected locale (--lang). When empty,
+	// OCTOSCREEN_LANG and then the system locale are used instead.
+	Lang string
+
+	// Theme selects the built-in or custom (XDG themes dir) theme
+	// loaded at startup. See themes.List for the available names.
+	Theme = themes.Default
 )
 
 const (
@@ -32,6 +44,7 @@ type UI struct {
 	UIState string
 
 	Notifications *Notifications
+	Translations  *translations.Translations
 
 	s *SplashPanel
 	b *BackgroundTask
@@ -40,21 +53,134 @@ type UI struct {
 	w *gtk.Window
 	t time.Time
 
+	theme       string
+	cssProvider *gtk.CssProvider
+	m           *metrics
+	cfg         *config.Config
+
 	width, height int
 	scaleFactor   int
 
 	sync.Mutex
 }
 
+// NewFromConfig builds a UI from a resolved Config (see the config
+// package for how file, env and flag values are merged), applying its
+// language, theme and metrics settings before delegating to New, and its
+// watchdog interval and error mercy period after. The Settings panel
+// reloads state by calling ui.ReloadConfig with an updated Config, rather
+// than restarting.
+func NewFromConfig(cfg *config.Config) *UI {
+	Lang = cfg.Lang
+	Theme = cfg.Theme
+	MetricsListen = cfg.MetricsListen
+	if cfg.MetricsPath != "" {
+		MetricsPath = cfg.MetricsPath
+	}
+
+	ui := New(cfg.Endpoint, cfg.APIKey, cfg.Width, cfg.Height)
+	ui.cfg = cfg
+	ui.applyWatchdogSettings()
+	return ui
+}
+
+// applyWatchdogSettings pushes cfg's watchdog interval and error mercy
+// period onto the running BackgroundTask and splash-screen tolerance.
+func (ui *UI) applyWatchdogSettings() {
+	if ui.cfg == nil {
+		return
+	}
+
+	if ui.cfg.WatchdogInterval > 0 {
+		ui.b.SetInterval(time.Duration(ui.cfg.WatchdogInterval) * time.Second)
+	}
+
+	if ui.cfg.ErrMercyPeriod > 0 {
+		errMercyPeriod = time.Duration(ui.cfg.ErrMercyPeriod) * time.Second
+	}
+}
+
+// ReloadConfig applies a freshly saved Config without restarting: it
+// recreates the OctoPrint client if the endpoint or key changed, swaps
+// the theme, resizes the window, and re-runs the watchdog immediately so
+// the new settings take effect right away.
+//
+// ui.Printer and ui.Translations are also read by verifyConnection on
+// BackgroundTask's own goroutine, so they're never assigned directly
+// here: every fallible step (SetTheme) runs first, and only once all of
+// them succeed is the actual swap handed to BackgroundTask.Reconfigure,
+// which applies it on that same goroutine and re-verifies the
+// connection immediately, atomically and without racing the ticker.
+func (ui *UI) ReloadConfig(cfg *config.Config) error {
+	ui.Lock()
+	defer ui.Unlock()
+
+	prev := ui.cfg
+
+	if prev == nil || cfg.Theme != prev.Theme {
+		if err := ui.SetTheme(cfg.Theme); err != nil {
+			return err
+		}
+	}
+
+	var printer *octoprint.Client
+	if prev == nil || cfg.Endpoint != prev.Endpoint || cfg.APIKey != prev.APIKey {
+		printer = octoprint.NewClient(cfg.Endpoint, cfg.APIKey)
+	}
+
+	langChanged := prev == nil || cfg.Lang != prev.Lang
+
+	ui.b.Reconfigure(func() {
+		if printer != nil {
+			ui.Printer = printer
+		}
+
+		if langChanged {
+			ui.Translations.SetLocale(cfg.Lang)
+		}
+
+		ui.cfg = cfg
+		ui.applyWatchdogSettings()
+		ui.verifyConnection()
+	})
+
+	if prev == nil || cfg.Width != prev.Width || cfg.Height != prev.Height {
+		ui.width, ui.height = cfg.Width, cfg.Height
+		ui.w.Resize(cfg.Width, cfg.Height)
+	}
+
+	if langChanged {
+		if r, ok := ui.Current.(Refreshable); ok {
+			r.Refresh()
+		}
+	}
+
+	return nil
+}
+
+// Refreshable is implemented by panels whose displayed text is built
+// from ui.T at construction time; ReloadConfig calls Refresh on the
+// current panel after a language change so its text updates immediately
+// rather than on the next panel transition.
+type Refreshable interface {
+	Refresh()
+}
+
 func New(endpoint, key string, width, height int) *UI {
 	if width == 0 || height == 0 {
 		width = WindowWidth
 		height = WindowHeight
 	}
 
+	tr, err := translations.Load(locale(), translations.ConfigDir())
+	if err != nil {
+		logrus.Errorf("Error loading translations: %s", err)
+	}
+
 	ui := &UI{
 		Printer:       octoprint.NewClient(endpoint, key),
 		Notifications: NewNotifications(),
+		Translations:  tr,
 
 		w: MustWindow(gtk.WINDOW_TOPLEVEL),
 		t: time.Now(),
@@ -74,10 +200,32 @@ func New(endpoint, key string, width, height int) *UI {
 
 	ui.s = NewSplashPanel(ui)
 	ui.b = NewBackgroundTask(time.Second*5, ui.verifyConnection)
+	ui.m = newMetrics()
+	ui.m.start()
 	ui.initialize()
 	return ui
 }
 
+// locale resolves the active locale from, in order of precedence, the
+// --lang flag (Lang), the OCTOSCREEN_LANG env var, and the system locale.
+func locale() string {
+	if Lang != "" {
+		return Lang
+	}
+
+	if lang := os.Getenv("OCTOSCREEN_LANG"); lang != "" {
+		return lang
+	}
+
+	return translations.DetectLocale()
+}
+
+// T resolves key against the active translation catalog. See
+// translations.Translations.T for formatting and fallback behaviour.
+func (ui *UI) T(key string, args ...interface{}) string {
+	return ui.Translations.T(key, args...)
+}
+
 func (ui *UI) initialize() {
 	defer ui.w.ShowAll()
 	ui.loadStyle()
@@ -88,6 +236,7 @@ func (ui *UI) initialize() {
 
 	ui.w.Connect("show", ui.b.Start)
 	ui.w.Connect("destroy", func() {
+		ui.m.stop()
 		gtk.MainQuit()
 	})
 
@@ -102,15 +251,66 @@ func (ui *UI) initialize() {
 }
 
 func (ui *UI) loadStyle() {
-	p := MustCSSProviderFromFile(CSSFilename)
+	if err := ui.SetTheme(Theme); err != nil {
+		logrus.Errorf("Error loading theme %q: %s", Theme, err)
+	}
+}
+
+// SetTheme swaps the active gtk.CssProvider on the default GDK screen for
+// the named theme (built-in, or custom from themes.ConfigDir), removing
+// whichever provider was previously installed, and re-applies ShowAll so
+// every attached panel repaints with the new styles.
+func (ui *UI) SetTheme(name string) error {
+	manifest, err := themes.Load(name, themes.ConfigDir())
+	if err != nil {
+		return err
+	}
+
+	p, err := cssProviderFromData(manifest.CSS())
+	if err != nil {
+		return err
+	}
 
 	s, err := gdk.ScreenGetDefault()
 	if err != nil {
-		logrus.Errorf("Error getting GDK screen: %s", err)
-		return
+		return fmt.Errorf("getting GDK screen: %s", err)
+	}
+
+	if ui.cssProvider != nil {
+		gtk.RemoveProviderForScreen(s, ui.cssProvider)
 	}
 
 	gtk.AddProviderForScreen(s, p, gtk.STYLE_PROVIDER_PRIORITY_USER)
+	ui.cssProvider = p
+	ui.theme = manifest.ID()
+
+	if ui.w != nil {
+		ui.w.ShowAll()
+	}
+
+	return nil
+}
+
+// CurrentTheme returns the ID of the theme currently applied, e.g.
+// "dark", for use by status indicators that pick accent colors.
+func (ui *UI) CurrentTheme() string {
+	return ui.theme
+}
+
+// cssProviderFromData builds a gtk.CssProvider from raw CSS, for
+// themes loaded from an embedded or user-provided stylesheet rather than
+// a fixed file on disk (see MustCSSProviderFromFile for that case).
+func cssProviderFromData(css []byte) (*gtk.CssProvider, error) {
+	p, err := gtk.CssProviderNew()
+	if err != nil {
+		return nil, fmt.Errorf("creating CSS provider: %s", err)
+	}
+
+	if err := p.LoadFromData(string(css)); err != nil {
+		return nil, fmt.Errorf("loading CSS: %s", err)
+	}
+
+	return p, nil
 }
 
 var errMercyPeriod = time.Second * 30
@@ -124,20 +324,24 @@ func (ui *UI) verifyConnection() {
 	s, err := (&octoprint.ConnectionRequest{}).Do(ui.Printer)
 	if err == nil {
 		ui.State = s.Current.State
+		ui.m.setConnectionState(s.Current.State)
+
 		switch {
 		case s.Current.State.IsOperational():
 			newUiState = "idle"
+			ui.m.updateJobMetrics(ui.Printer)
 		case s.Current.State.IsPrinting():
 			newUiState = "printing"
+			ui.m.updateJobMetrics(ui.Printer)
 		case s.Current.State.IsError():
 			fallthrough
 		case s.Current.State.IsOffline():
 			if err := (&octoprint.ConnectRequest{}).Do(ui.Printer); err != nil {
 				newUiState = "splash"
-				ui.s.Label.SetText(fmt.Sprintf("Error connecting to printer: %s", err))
+				ui.s.Label.SetText(ui.T("connect.unexpected", err))
 			}
 		case s.Current.State.IsConnecting():
-			ui.s.Label.SetText(string(s.Current.State))
+			ui.s.Label.SetText(ui.T("state.connecting"))
 		}
 	} else {
 		if time.Since(ui.t) > errMercyPeriod {
@@ -145,10 +349,12 @@ func (ui *UI) verifyConnection() {
 		}
 
 		newUiState = "splash"
+		ui.m.connectionErrors.Inc()
 		Logger.Debugf("Unexpected error: %s", err)
 	}
 
 	defer func() { ui.UIState = newUiState }()
+	defer ui.m.setUIState(newUiState)
 
 	if newUiState == ui.UIState {
 		return
@@ -156,10 +362,10 @@ func (ui *UI) verifyConnection() {
 
 	switch newUiState {
 	case "idle":
-		Logger.Info("Printer is ready")
+		Logger.Info(ui.T("state.ready"))
 		ui.Add(IdleStatusPanel(ui))
 	case "printing":
-		Logger.Info("Printing a job")
+		Logger.Info(ui.T("state.printing"))
 		ui.Add(PrintStatusPanel(ui))
 	case "splash":
 		ui.Add(ui.s)
@@ -198,11 +404,8 @@ func (ui *UI) GoHistory() {
 func (ui *UI) errToUser(err error) string {
 	text := err.Error()
 	if strings.Contains(text, "connection refused") {
-		return fmt.Sprintf(
-			"Unable to connect to %q (Key: %v), \nmaybe OctoPrint not running?",
-			ui.Printer.Endpoint, ui.Printer.APIKey != "",
-		)
+		return ui.T("connect.refused", ui.Printer.Endpoint, ui.Printer.APIKey != "")
 	}
 
-	return fmt.Sprintf("Unexpected error: %s", err)
+	return ui.T("connect.unexpected", err)
 }